@@ -0,0 +1,80 @@
+package bucketeer
+
+import "github.com/boltdb/bolt"
+
+/*
+KeyValue pairs a Key with the raw bytes to store under it, for use with PutMany. Key cannot be used as a map key here because several Key implementations (BinaryKey, TextKey, JsonKey) are backed by byte slices, which are not comparable.
+*/
+type KeyValue struct {
+	Key   Key
+	Value []byte
+}
+
+/*
+PutMany writes all of the provided key/value pairs inside a single transaction, updating every Index registered against bb via DefineIndex for each pair and replacing any stale entries left by whatever value previously lived under each key.
+*/
+func (bb *Bucketeer) PutMany(entries []KeyValue) error {
+	bf := func(b *bolt.Bucket) error {
+		for _, entry := range entries {
+			if err := bb.reindexingPut(b, entry.Key.KeyBytes(), entry.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return bb.Update(bf)
+}
+
+/*
+GetMany retrieves the values for the provided keys inside a single transaction. The returned map is keyed by the string form of each key's raw bytes; keys with no value in the bucket are omitted.
+*/
+func (bb *Bucketeer) GetMany(keys []Key) (values map[string][]byte, err error) {
+	values = make(map[string][]byte, len(keys))
+	bf := func(b *bolt.Bucket) error {
+		for _, key := range keys {
+			keyBytes := key.KeyBytes()
+			if value := b.Get(keyBytes); value != nil {
+				values[string(keyBytes)] = append([]byte(nil), value...)
+			}
+		}
+		return nil
+	}
+	err = bb.View(bf)
+	return
+}
+
+/*
+DeleteMany deletes all of the provided keys inside a single transaction, removing each from every Index registered against bb via DefineIndex.
+*/
+func (bb *Bucketeer) DeleteMany(keys []Key) error {
+	bf := func(b *bolt.Bucket) error {
+		for _, key := range keys {
+			keyBytes := key.KeyBytes()
+			value := b.Get(keyBytes)
+			if value == nil {
+				continue
+			}
+			if err := bb.removeIndexes(b, keyBytes, value); err != nil {
+				return err
+			}
+			if err := b.Delete(keyBytes); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return bb.Update(bf)
+}
+
+/*
+UpdateBatch executes updateFunc via db.Batch, which amortizes the fsync cost of an Update transaction across concurrent callers.
+*/
+func (bb *Bucketeer) UpdateBatch(updateFunc func(b *bolt.Bucket) error) error {
+	txf := func(tx *bolt.Tx) error {
+		if b := GetBucket(tx, bb.path); b != nil {
+			return updateFunc(b)
+		}
+		return nil
+	}
+	return bb.db.Batch(txf)
+}
@@ -0,0 +1,53 @@
+package bucketeer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/boltdb/bolt"
+)
+
+// sliceKey is a Key backed by a slice, standing in for BinaryKey/TextKey/JsonKey,
+// none of which are comparable and so cannot be used as a map key.
+type sliceKey []byte
+
+func (k sliceKey) KeyBytes() []byte { return []byte(k) }
+
+func TestPutManyWithSliceBackedKeys(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "batch")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := []KeyValue{
+		{Key: sliceKey("a"), Value: []byte("1")},
+		{Key: sliceKey("b"), Value: []byte("2")},
+	}
+	if err := bb.PutMany(entries); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := bb.GetMany([]Key{sliceKey("a"), sliceKey("b")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(values["a"], []byte("1")) {
+		t.Errorf("values[a] = %q, want %q", values["a"], "1")
+	}
+	if !bytes.Equal(values["b"], []byte("2")) {
+		t.Errorf("values[b] = %q, want %q", values["b"], "2")
+	}
+
+	if err := bb.DeleteMany([]Key{sliceKey("a")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bb.View(func(b *bolt.Bucket) error {
+		if v := b.Get([]byte("a")); v != nil {
+			t.Errorf("expected key a to be deleted, got %q", v)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
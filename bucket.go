@@ -11,8 +11,9 @@ import (
 Bucketeer encapsulates the components needed to resolve a bucket in BoltDB and provides convenience methods for initializing Keyfarers for various key types.
 */
 type Bucketeer struct {
-	db   *bolt.DB
-	path Path
+	db      *bolt.DB
+	path    Path
+	indexes []*Index
 }
 
 /*
@@ -0,0 +1,114 @@
+package bucketeer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/boltdb/bolt"
+)
+
+const (
+	metaRootBucket   = "__meta__"
+	versionedMetaKey = "versioned"
+)
+
+/*
+CompareAndSwap atomically replaces the value under the Keyfarer's key with new, but only if the current value matches expected exactly. It reports whether the swap occurred, and updates every Index registered against the Keyfarer's Bucketeer when it does, removing whatever entries expected left behind.
+*/
+func (kf *Keyfarer) CompareAndSwap(expected, new []byte) (swapped bool, err error) {
+	bf := func(b *bolt.Bucket) error {
+		if !bytes.Equal(b.Get(kf.key), expected) {
+			return nil
+		}
+		swapped = true
+		return kf.bb.reindexingPut(b, kf.key, new)
+	}
+	err = kf.bb.Update(bf)
+	return
+}
+
+/*
+AtomicDelete atomically deletes the value under the Keyfarer's key, but only if the current value matches expected exactly. It reports whether the delete occurred, and removes the key from every Index registered against the Keyfarer's Bucketeer when it does.
+*/
+func (kf *Keyfarer) AtomicDelete(expected []byte) (deleted bool, err error) {
+	bf := func(b *bolt.Bucket) error {
+		current := b.Get(kf.key)
+		if !bytes.Equal(current, expected) {
+			return nil
+		}
+		deleted = true
+		if err := kf.bb.removeIndexes(b, kf.key, current); err != nil {
+			return err
+		}
+		return b.Delete(kf.key)
+	}
+	err = kf.bb.Update(bf)
+	return
+}
+
+/*
+EnableVersioning marks the Bucketeer's bucket as versioned, persisting the setting in a reserved "__meta__" sub-bucket (mirroring how Index isolates its "__idx__" sub-bucket) so it never collides with a real stored key and survives process restarts. Once enabled, PutVersioned and GetVersioned can be used to read and write revision-tagged values for any key in the bucket; PutVersioned refuses to run until this has been called.
+*/
+func (bb *Bucketeer) EnableVersioning() error {
+	if err := bb.EnsureNestedBucket(metaRootBucket); err != nil {
+		return err
+	}
+	bf := func(b *bolt.Bucket) error {
+		return b.Put([]byte(versionedMetaKey), []byte{1})
+	}
+	return UpdateInBucket(bb.db, bb.path.Nest(metaRootBucket), bf)
+}
+
+/*
+IsVersioned reports whether the Bucketeer's bucket has versioning enabled via EnableVersioning.
+*/
+func (bb *Bucketeer) IsVersioned() (versioned bool, err error) {
+	bf := func(b *bolt.Bucket) error {
+		versioned = b.Get([]byte(versionedMetaKey)) != nil
+		return nil
+	}
+	err = ViewInBucket(bb.db, bb.path.Nest(metaRootBucket), bf)
+	return
+}
+
+/*
+PutVersioned writes value under the Keyfarer's key with an 8-byte little-endian revision counter prepended, incrementing the counter from whatever was previously stored. It returns the revision that was written. It returns an error if EnableVersioning has not been called on the Keyfarer's Bucketeer.
+*/
+func (kf *Keyfarer) PutVersioned(value []byte) (revision uint64, err error) {
+	versioned, err := kf.bb.IsVersioned()
+	if err != nil {
+		return 0, err
+	}
+	if !versioned {
+		return 0, fmt.Errorf("bucketeer: PutVersioned requires EnableVersioning to have been called on this bucket")
+	}
+	bf := func(b *bolt.Bucket) error {
+		if current := b.Get(kf.key); len(current) >= 8 {
+			revision = binary.LittleEndian.Uint64(current[:8]) + 1
+		}
+		data := make([]byte, 8+len(value))
+		binary.LittleEndian.PutUint64(data, revision)
+		copy(data[8:], value)
+		return kf.bb.reindexingPut(b, kf.key, data)
+	}
+	err = kf.bb.Update(bf)
+	return
+}
+
+/*
+GetVersioned retrieves the value under the Keyfarer's key, stripping and returning the revision counter prepended by PutVersioned.
+*/
+func (kf *Keyfarer) GetVersioned() (value []byte, revision uint64, err error) {
+	bf := func(b *bolt.Bucket) error {
+		data := b.Get(kf.key)
+		if len(data) < 8 {
+			return fmt.Errorf("bucketeer: no versioned value found for key")
+		}
+		revision = binary.LittleEndian.Uint64(data[:8])
+		value = append([]byte(nil), data[8:]...)
+		return nil
+	}
+	err = kf.bb.View(bf)
+	return
+}
@@ -0,0 +1,121 @@
+package bucketeer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPutVersionedRequiresEnableVersioning(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "cas")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	kf := bb.ForStringKey("k")
+	if _, err := kf.PutVersioned([]byte("v")); err == nil {
+		t.Fatal("expected PutVersioned to fail before EnableVersioning is called")
+	}
+}
+
+func TestPutGetVersionedRoundTrip(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "cas")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bb.EnableVersioning(); err != nil {
+		t.Fatal(err)
+	}
+	kf := bb.ForStringKey("k")
+
+	rev, err := kf.PutVersioned([]byte("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rev != 0 {
+		t.Errorf("first PutVersioned revision = %d, want 0", rev)
+	}
+
+	rev, err = kf.PutVersioned([]byte("second"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rev != 1 {
+		t.Errorf("second PutVersioned revision = %d, want 1", rev)
+	}
+
+	value, revision, err := kf.GetVersioned()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value, []byte("second")) {
+		t.Errorf("GetVersioned value = %q, want %q", value, "second")
+	}
+	if revision != 1 {
+		t.Errorf("GetVersioned revision = %d, want 1", revision)
+	}
+}
+
+func TestEnableVersioningDoesNotLeakFlatValue(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "cas")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bb.EnableVersioning(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The versioning flag must live in a nested "__meta__" bucket, like Index's
+	// "__idx__", rather than as a flat value that could collide with a real
+	// stored key or show up as bogus data to ForEach callers. Walk and
+	// ExportJSON still descend into it like any other nested bucket.
+	var values []string
+	if err := bb.Cursor().ForEach(func(key, value []byte) error {
+		if value != nil {
+			values = append(values, string(key))
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 0 {
+		t.Errorf("expected no flat values after EnableVersioning, got %v", values)
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "cas")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	kf := bb.ForStringKey("k")
+	if err := kf.Put([]byte("old")); err != nil {
+		t.Fatal(err)
+	}
+
+	swapped, err := kf.CompareAndSwap([]byte("wrong"), []byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swapped {
+		t.Error("expected CompareAndSwap to fail on mismatched expected value")
+	}
+
+	swapped, err = kf.CompareAndSwap([]byte("old"), []byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !swapped {
+		t.Error("expected CompareAndSwap to succeed on matching expected value")
+	}
+
+	got, err := kf.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, []byte("new")) {
+		t.Errorf("value after CompareAndSwap = %q, want %q", got, "new")
+	}
+}
@@ -0,0 +1,176 @@
+package bucketeer
+
+import (
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+)
+
+/*
+Codec marshals and unmarshals values of arbitrary Go types for storage via Keyfarer.PutCodec and GetCodec, letting callers plug in gob, msgpack, protobuf, or any other encoding.
+*/
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+/*
+PutCodec marshals v with codec and stores the result under the Keyfarer's key.
+*/
+func (kf *Keyfarer) PutCodec(v interface{}, codec Codec) error {
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return kf.putIndexed(data)
+}
+
+/*
+GetCodec retrieves the value under the Keyfarer's key and unmarshals it into v with codec.
+*/
+func (kf *Keyfarer) GetCodec(v interface{}, codec Codec) error {
+	data, err := kf.Get()
+	if err != nil {
+		return err
+	}
+	return codec.Unmarshal(data, v)
+}
+
+/*
+PutJson marshals v to JSON and stores it under the Keyfarer's key.
+*/
+func (kf *Keyfarer) PutJson(v interface{}) error {
+	return kf.PutCodec(v, jsonCodec{})
+}
+
+/*
+GetJson retrieves the value under the Keyfarer's key and unmarshals it from JSON into v.
+*/
+func (kf *Keyfarer) GetJson(v interface{}) error {
+	return kf.GetCodec(v, jsonCodec{})
+}
+
+/*
+PutBinary marshals v via encoding.BinaryMarshaler and stores the result under the Keyfarer's key.
+*/
+func (kf *Keyfarer) PutBinary(v encoding.BinaryMarshaler) error {
+	data, err := v.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return kf.putIndexed(data)
+}
+
+/*
+GetBinary retrieves the value under the Keyfarer's key and unmarshals it via encoding.BinaryUnmarshaler.
+*/
+func (kf *Keyfarer) GetBinary(v encoding.BinaryUnmarshaler) error {
+	data, err := kf.Get()
+	if err != nil {
+		return err
+	}
+	return v.UnmarshalBinary(data)
+}
+
+/*
+PutText marshals v via encoding.TextMarshaler and stores the result under the Keyfarer's key.
+*/
+func (kf *Keyfarer) PutText(v encoding.TextMarshaler) error {
+	data, err := v.MarshalText()
+	if err != nil {
+		return err
+	}
+	return kf.putIndexed(data)
+}
+
+/*
+GetText retrieves the value under the Keyfarer's key and unmarshals it via encoding.TextUnmarshaler.
+*/
+func (kf *Keyfarer) GetText(v encoding.TextUnmarshaler) error {
+	data, err := kf.Get()
+	if err != nil {
+		return err
+	}
+	return v.UnmarshalText(data)
+}
+
+/*
+PutUint64 stores v in big-endian, fixed-length format under the Keyfarer's key, matching the Uint64Key encoding.
+*/
+func (kf *Keyfarer) PutUint64(v uint64) error {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, v)
+	return kf.putIndexed(data)
+}
+
+/*
+GetUint64 retrieves the value under the Keyfarer's key and decodes it as a big-endian uint64.
+*/
+func (kf *Keyfarer) GetUint64() (uint64, error) {
+	data, err := kf.Get()
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != 8 {
+		return 0, fmt.Errorf("bucketeer: value is %d bytes, want 8 for a uint64", len(data))
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+/*
+PutInt64 stores v shifted to always be a positive number, in big-endian, fixed-length format, matching the Int64Key encoding.
+*/
+func (kf *Keyfarer) PutInt64(v int64) error {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, uint64(v)^int64Shift)
+	return kf.putIndexed(data)
+}
+
+/*
+GetInt64 retrieves the value under the Keyfarer's key and decodes it, reversing the Int64Key shift.
+*/
+func (kf *Keyfarer) GetInt64() (int64, error) {
+	data, err := kf.Get()
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != 8 {
+		return 0, fmt.Errorf("bucketeer: value is %d bytes, want 8 for an int64", len(data))
+	}
+	return int64(binary.BigEndian.Uint64(data) ^ int64Shift), nil
+}
+
+/*
+PutProto marshals v as a protobuf message and stores the result under the Keyfarer's key.
+*/
+func (kf *Keyfarer) PutProto(v proto.Message) error {
+	data, err := proto.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return kf.putIndexed(data)
+}
+
+/*
+GetProto retrieves the value under the Keyfarer's key and unmarshals it as a protobuf message.
+*/
+func (kf *Keyfarer) GetProto(v proto.Message) error {
+	data, err := kf.Get()
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, v)
+}
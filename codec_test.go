@@ -0,0 +1,53 @@
+package bucketeer
+
+import "testing"
+
+func TestGetUint64MissingKeyReturnsError(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "codec")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	kf := bb.ForStringKey("missing")
+	if _, err := kf.GetUint64(); err == nil {
+		t.Fatal("expected an error reading a uint64 from a missing key, got nil")
+	}
+}
+
+func TestPutGetUint64RoundTrip(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "codec")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	kf := bb.ForStringKey("count")
+	if err := kf.PutUint64(42); err != nil {
+		t.Fatal(err)
+	}
+	got, err := kf.GetUint64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 42 {
+		t.Errorf("GetUint64() = %d, want 42", got)
+	}
+}
+
+func TestPutGetInt64RoundTrip(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "codec")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	kf := bb.ForStringKey("delta")
+	if err := kf.PutInt64(-17); err != nil {
+		t.Fatal(err)
+	}
+	got, err := kf.GetInt64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != -17 {
+		t.Errorf("GetInt64() = %d, want -17", got)
+	}
+}
@@ -0,0 +1,174 @@
+package bucketeer
+
+import "github.com/boltdb/bolt"
+
+const indexRootBucket = "__idx__"
+
+/*
+Index maintains a secondary lookup bucket for a Bucketeer, mapping index keys derived from each stored entry back to the primary keys that produced them. Indexes are created with Bucketeer.DefineIndex and kept up to date by Bucketeer.PutIndexed and Bucketeer.DeleteIndexed.
+*/
+type Index struct {
+	bb      *Bucketeer
+	name    string
+	keyFunc func(key, value []byte) ([][]byte, error)
+}
+
+/*
+DefineIndex registers an Index named name against bb and returns it. keyFunc derives zero or more index keys from a primary key/value pair; PutIndexed and DeleteIndexed keep every registered Index for bb up to date in the same transaction as the primary write.
+*/
+func (bb *Bucketeer) DefineIndex(name string, keyFunc func(key, value []byte) ([][]byte, error)) *Index {
+	idx := &Index{bb: bb, name: name, keyFunc: keyFunc}
+	bb.indexes = append(bb.indexes, idx)
+	return idx
+}
+
+func (idx *Index) path() Path {
+	return idx.bb.path.Nest(indexRootBucket).Nest(idx.name)
+}
+
+/*
+Lookup returns the primary keys currently associated with indexKey.
+*/
+func (idx *Index) Lookup(indexKey []byte) (primaryKeys [][]byte, err error) {
+	bf := func(b *bolt.Bucket) error {
+		sub := b.Bucket(indexKey)
+		if sub == nil {
+			return nil
+		}
+		return sub.ForEach(func(k, _ []byte) error {
+			primaryKeys = append(primaryKeys, append([]byte(nil), k...))
+			return nil
+		})
+	}
+	err = ViewInBucket(idx.bb.db, idx.path(), bf)
+	return
+}
+
+func (idx *Index) updateTx(b *bolt.Bucket, primaryKey, value []byte) error {
+	indexKeys, err := idx.keyFunc(primaryKey, value)
+	if err != nil {
+		return err
+	}
+	root, err := b.CreateBucketIfNotExists([]byte(indexRootBucket))
+	if err != nil {
+		return err
+	}
+	named, err := root.CreateBucketIfNotExists([]byte(idx.name))
+	if err != nil {
+		return err
+	}
+	for _, indexKey := range indexKeys {
+		sub, err := named.CreateBucketIfNotExists(indexKey)
+		if err != nil {
+			return err
+		}
+		if err := sub.Put(primaryKey, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (idx *Index) removeTx(b *bolt.Bucket, primaryKey, value []byte) error {
+	indexKeys, err := idx.keyFunc(primaryKey, value)
+	if err != nil {
+		return err
+	}
+	root := b.Bucket([]byte(indexRootBucket))
+	if root == nil {
+		return nil
+	}
+	named := root.Bucket([]byte(idx.name))
+	if named == nil {
+		return nil
+	}
+	for _, indexKey := range indexKeys {
+		sub := named.Bucket(indexKey)
+		if sub == nil {
+			continue
+		}
+		if err := sub.Delete(primaryKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyIndexes updates every Index registered against bb for a key/value pair that was just
+// written at keyBytes. It must be called from inside the same bolt.Tx as the write.
+func (bb *Bucketeer) applyIndexes(b *bolt.Bucket, keyBytes, value []byte) error {
+	for _, idx := range bb.indexes {
+		if err := idx.updateTx(b, keyBytes, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeIndexes removes keyBytes/value from every Index registered against bb. It must be
+// called from inside the same bolt.Tx as the delete, before the value is actually removed.
+func (bb *Bucketeer) removeIndexes(b *bolt.Bucket, keyBytes, value []byte) error {
+	for _, idx := range bb.indexes {
+		if err := idx.removeTx(b, keyBytes, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reindexingPut writes value under keyBytes in b, first removing any stale Index entries left
+// over from the previous value at that key (if any), then applying every registered Index to
+// the new value. It must be called from inside the caller's own bolt.Tx.
+func (bb *Bucketeer) reindexingPut(b *bolt.Bucket, keyBytes, value []byte) error {
+	if old := b.Get(keyBytes); old != nil {
+		if err := bb.removeIndexes(b, keyBytes, old); err != nil {
+			return err
+		}
+	}
+	if err := b.Put(keyBytes, value); err != nil {
+		return err
+	}
+	return bb.applyIndexes(b, keyBytes, value)
+}
+
+// putIndexed writes value under kf's key and updates every Index registered against kf's
+// Bucketeer, all inside a single transaction, replacing any stale entries left by the value
+// previously stored at that key. It backs Keyfarer.Put and the typed Put methods (PutJson,
+// PutBinary, PutText, PutUint64, PutInt64, PutProto, PutCodec); PutVersioned and the write
+// side of CompareAndSwap call reindexingPut directly inside their own transactions, so
+// defining an Index keeps them all in sync.
+func (kf *Keyfarer) putIndexed(value []byte) error {
+	bf := func(b *bolt.Bucket) error {
+		return kf.bb.reindexingPut(b, kf.key, value)
+	}
+	return kf.bb.Update(bf)
+}
+
+/*
+PutIndexed stores value under key, updating every Index registered against bb via DefineIndex and replacing any stale entries left by the value previously stored at key, all inside a single transaction. This is the Bucketeer-level counterpart of Keyfarer.Put (and the typed Keyfarer methods built on it) for callers that only have a Key rather than a Keyfarer.
+*/
+func (bb *Bucketeer) PutIndexed(key Key, value []byte) error {
+	keyBytes := key.KeyBytes()
+	bf := func(b *bolt.Bucket) error {
+		return bb.reindexingPut(b, keyBytes, value)
+	}
+	return bb.Update(bf)
+}
+
+/*
+DeleteIndexed deletes the value under key and removes it from every Index registered against bb via DefineIndex, all inside a single transaction.
+*/
+func (bb *Bucketeer) DeleteIndexed(key Key) error {
+	keyBytes := key.KeyBytes()
+	bf := func(b *bolt.Bucket) error {
+		value := b.Get(keyBytes)
+		if value == nil {
+			return nil
+		}
+		if err := bb.removeIndexes(b, keyBytes, value); err != nil {
+			return err
+		}
+		return b.Delete(keyBytes)
+	}
+	return bb.Update(bf)
+}
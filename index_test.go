@@ -0,0 +1,228 @@
+package bucketeer
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+type jsonRecord struct {
+	Email string `json:"email"`
+}
+
+func byEmailIndexFunc(key, value []byte) ([][]byte, error) {
+	var rec jsonRecord
+	if err := json.Unmarshal(value, &rec); err != nil {
+		return nil, err
+	}
+	if rec.Email == "" {
+		return nil, nil
+	}
+	return [][]byte{[]byte(rec.Email)}, nil
+}
+
+func TestBasePutAndDeleteMaintainRegisteredIndex(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "records")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	byFirstByte := bb.DefineIndex("byFirstByte", func(key, value []byte) ([][]byte, error) {
+		if len(value) == 0 {
+			return nil, nil
+		}
+		return [][]byte{value[:1]}, nil
+	})
+
+	kf := bb.ForStringKey("user-0")
+	if err := kf.Put([]byte("apple")); err != nil {
+		t.Fatal(err)
+	}
+	primaryKeys, err := byFirstByte.Lookup([]byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primaryKeys) != 1 || !bytes.Equal(primaryKeys[0], []byte("user-0")) {
+		t.Fatalf("Lookup(a) after Put = %v, want [user-0]", primaryKeys)
+	}
+
+	if err := kf.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	primaryKeys, err = byFirstByte.Lookup([]byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primaryKeys) != 0 {
+		t.Fatalf("Lookup(a) after Delete = %v, want none", primaryKeys)
+	}
+}
+
+func TestPutJsonUpdatesRegisteredIndex(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "records")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	idx := bb.DefineIndex("byEmail", byEmailIndexFunc)
+
+	kf := bb.ForStringKey("user-1")
+	if err := kf.PutJson(jsonRecord{Email: "a@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	primaryKeys, err := idx.Lookup([]byte("a@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primaryKeys) != 1 || !bytes.Equal(primaryKeys[0], []byte("user-1")) {
+		t.Fatalf("Lookup(a@example.com) = %v, want [user-1]", primaryKeys)
+	}
+}
+
+func TestPutManyUpdatesRegisteredIndex(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "records")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	idx := bb.DefineIndex("byEmail", byEmailIndexFunc)
+
+	value, err := json.Marshal(jsonRecord{Email: "b@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bb.PutMany([]KeyValue{{Key: NewStringKey("user-2"), Value: value}}); err != nil {
+		t.Fatal(err)
+	}
+
+	primaryKeys, err := idx.Lookup([]byte("b@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primaryKeys) != 1 || !bytes.Equal(primaryKeys[0], []byte("user-2")) {
+		t.Fatalf("Lookup(b@example.com) = %v, want [user-2]", primaryKeys)
+	}
+
+	if err := bb.DeleteMany([]Key{NewStringKey("user-2")}); err != nil {
+		t.Fatal(err)
+	}
+	primaryKeys, err = idx.Lookup([]byte("b@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primaryKeys) != 0 {
+		t.Fatalf("Lookup(b@example.com) after DeleteMany = %v, want none", primaryKeys)
+	}
+}
+
+func TestCompareAndSwapUpdatesRegisteredIndex(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "records")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	idx := bb.DefineIndex("byEmail", byEmailIndexFunc)
+
+	kf := bb.ForStringKey("user-3")
+	oldValue, err := json.Marshal(jsonRecord{Email: "old@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := kf.PutJson(jsonRecord{Email: "old@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	newValue, err := json.Marshal(jsonRecord{Email: "new@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	swapped, err := kf.CompareAndSwap(oldValue, newValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !swapped {
+		t.Fatal("expected CompareAndSwap to succeed")
+	}
+
+	primaryKeys, err := idx.Lookup([]byte("new@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primaryKeys) != 1 {
+		t.Fatalf("Lookup(new@example.com) = %v, want [user-3]", primaryKeys)
+	}
+
+	staleKeys, err := idx.Lookup([]byte("old@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(staleKeys) != 0 {
+		t.Fatalf("Lookup(old@example.com) after swap = %v, want none", staleKeys)
+	}
+}
+
+func TestPutJsonOverwriteRemovesStaleIndexEntry(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "records")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	idx := bb.DefineIndex("byEmail", byEmailIndexFunc)
+
+	kf := bb.ForStringKey("user-4")
+	if err := kf.PutJson(jsonRecord{Email: "first@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := kf.PutJson(jsonRecord{Email: "second@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	staleKeys, err := idx.Lookup([]byte("first@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(staleKeys) != 0 {
+		t.Fatalf("Lookup(first@example.com) after overwrite = %v, want none", staleKeys)
+	}
+
+	primaryKeys, err := idx.Lookup([]byte("second@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(primaryKeys) != 1 || !bytes.Equal(primaryKeys[0], []byte("user-4")) {
+		t.Fatalf("Lookup(second@example.com) = %v, want [user-4]", primaryKeys)
+	}
+}
+
+func TestPutManyOverwriteRemovesStaleIndexEntry(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "records")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	idx := bb.DefineIndex("byEmail", byEmailIndexFunc)
+
+	firstValue, err := json.Marshal(jsonRecord{Email: "first@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondValue, err := json.Marshal(jsonRecord{Email: "second@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bb.PutMany([]KeyValue{{Key: NewStringKey("user-5"), Value: firstValue}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bb.PutMany([]KeyValue{{Key: NewStringKey("user-5"), Value: secondValue}}); err != nil {
+		t.Fatal(err)
+	}
+
+	staleKeys, err := idx.Lookup([]byte("first@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(staleKeys) != 0 {
+		t.Fatalf("Lookup(first@example.com) after overwrite = %v, want none", staleKeys)
+	}
+}
@@ -0,0 +1,228 @@
+package bucketeer
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+
+	"github.com/boltdb/bolt"
+)
+
+const int64Shift = uint64(1) << 63
+
+/*
+Cursorer wraps a bolt.Bucket cursor to provide typed iteration over a Bucketeer's keys, analogous to how Keyfarer wraps access to a single key.
+*/
+type Cursorer struct {
+	bb *Bucketeer
+}
+
+/*
+Cursor creates a new Cursorer for iterating over the Bucketeer's bucket.
+*/
+func (bb *Bucketeer) Cursor() *Cursorer {
+	return &Cursorer{bb: bb}
+}
+
+/*
+ForEach iterates over every key/value pair in the bucket in ascending key order, invoking fn with the raw key and value bytes. Iteration stops and returns fn's error if fn returns a non-nil error.
+*/
+func (c *Cursorer) ForEach(fn func(key, value []byte) error) error {
+	return c.bb.View(func(b *bolt.Bucket) error {
+		cur := b.Cursor()
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			if err := fn(k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+/*
+ReverseForEach iterates over every key/value pair in the bucket in descending key order, invoking fn with the raw key and value bytes.
+*/
+func (c *Cursorer) ReverseForEach(fn func(key, value []byte) error) error {
+	return c.bb.View(func(b *bolt.Bucket) error {
+		cur := b.Cursor()
+		for k, v := cur.Last(); k != nil; k, v = cur.Prev() {
+			if err := fn(k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+/*
+ForEachPrefix iterates over every key/value pair whose raw key begins with prefix, in ascending key order.
+*/
+func (c *Cursorer) ForEachPrefix(prefix []byte, fn func(key, value []byte) error) error {
+	return c.bb.View(func(b *bolt.Bucket) error {
+		cur := b.Cursor()
+		for k, v := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cur.Next() {
+			if err := fn(k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+/*
+ForRange iterates over every key/value pair whose raw key falls between lo and hi inclusive, in ascending key order.
+*/
+func (c *Cursorer) ForRange(lo, hi []byte, fn func(key, value []byte) error) error {
+	return c.bb.View(func(b *bolt.Bucket) error {
+		cur := b.Cursor()
+		for k, v := cur.Seek(lo); k != nil && bytes.Compare(k, hi) <= 0; k, v = cur.Next() {
+			if err := fn(k, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+/*
+ForEachUint64Key iterates over every key/value pair in the bucket in ascending key order, decoding each raw key as a Uint64Key.
+*/
+func (c *Cursorer) ForEachUint64Key(fn func(key uint64, value []byte) error) error {
+	return c.ForEach(func(k, v []byte) error {
+		return fn(binary.BigEndian.Uint64(k), v)
+	})
+}
+
+/*
+ReverseForEachUint64Key iterates over every key/value pair in the bucket in descending key order, decoding each raw key as a Uint64Key.
+*/
+func (c *Cursorer) ReverseForEachUint64Key(fn func(key uint64, value []byte) error) error {
+	return c.ReverseForEach(func(k, v []byte) error {
+		return fn(binary.BigEndian.Uint64(k), v)
+	})
+}
+
+/*
+ForRangeUint64Key iterates over every Uint64Key entry between lo and hi inclusive, in ascending key order.
+*/
+func (c *Cursorer) ForRangeUint64Key(lo, hi uint64, fn func(key uint64, value []byte) error) error {
+	loBytes, hiBytes := make([]byte, 8), make([]byte, 8)
+	binary.BigEndian.PutUint64(loBytes, lo)
+	binary.BigEndian.PutUint64(hiBytes, hi)
+	return c.ForRange(loBytes, hiBytes, func(k, v []byte) error {
+		return fn(binary.BigEndian.Uint64(k), v)
+	})
+}
+
+func encodeInt64Key(key int64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(key)^int64Shift)
+	return buf
+}
+
+func decodeInt64Key(raw []byte) int64 {
+	return int64(binary.BigEndian.Uint64(raw) ^ int64Shift)
+}
+
+/*
+ForEachInt64Key iterates over every key/value pair in the bucket in ascending key order, decoding each raw key as an Int64Key.
+*/
+func (c *Cursorer) ForEachInt64Key(fn func(key int64, value []byte) error) error {
+	return c.ForEach(func(k, v []byte) error {
+		return fn(decodeInt64Key(k), v)
+	})
+}
+
+/*
+ReverseForEachInt64Key iterates over every key/value pair in the bucket in descending key order, decoding each raw key as an Int64Key.
+*/
+func (c *Cursorer) ReverseForEachInt64Key(fn func(key int64, value []byte) error) error {
+	return c.ReverseForEach(func(k, v []byte) error {
+		return fn(decodeInt64Key(k), v)
+	})
+}
+
+/*
+ForRangeInt64Key iterates over every Int64Key entry between lo and hi inclusive, in ascending key order.
+*/
+func (c *Cursorer) ForRangeInt64Key(lo, hi int64, fn func(key int64, value []byte) error) error {
+	return c.ForRange(encodeInt64Key(lo), encodeInt64Key(hi), func(k, v []byte) error {
+		return fn(decodeInt64Key(k), v)
+	})
+}
+
+/*
+ForEachStringKey iterates over every key/value pair in the bucket in ascending key order, decoding each raw key as a StringKey.
+*/
+func (c *Cursorer) ForEachStringKey(fn func(key string, value []byte) error) error {
+	return c.ForEach(func(k, v []byte) error {
+		return fn(string(k), v)
+	})
+}
+
+/*
+ReverseForEachStringKey iterates over every key/value pair in the bucket in descending key order, decoding each raw key as a StringKey.
+*/
+func (c *Cursorer) ReverseForEachStringKey(fn func(key string, value []byte) error) error {
+	return c.ReverseForEach(func(k, v []byte) error {
+		return fn(string(k), v)
+	})
+}
+
+/*
+ForEachPrefixStringKey iterates over every StringKey entry whose key begins with prefix, in ascending key order.
+*/
+func (c *Cursorer) ForEachPrefixStringKey(prefix string, fn func(key string, value []byte) error) error {
+	return c.ForEachPrefix([]byte(prefix), func(k, v []byte) error {
+		return fn(string(k), v)
+	})
+}
+
+/*
+ForRangeStringKey iterates over every StringKey entry between lo and hi inclusive, in ascending key order.
+*/
+func (c *Cursorer) ForRangeStringKey(lo, hi string, fn func(key string, value []byte) error) error {
+	return c.ForRange([]byte(lo), []byte(hi), func(k, v []byte) error {
+		return fn(string(k), v)
+	})
+}
+
+/*
+ForEachTextKey iterates over every key/value pair in the bucket in ascending key order, unmarshaling each raw key via encoding.TextUnmarshaler into a fresh instance obtained from newKey.
+*/
+func (c *Cursorer) ForEachTextKey(newKey func() encoding.TextUnmarshaler, fn func(key encoding.TextUnmarshaler, value []byte) error) error {
+	return c.ForEach(func(k, v []byte) error {
+		key := newKey()
+		if err := key.UnmarshalText(k); err != nil {
+			return err
+		}
+		return fn(key, v)
+	})
+}
+
+/*
+ForEachBinaryKey iterates over every key/value pair in the bucket in ascending key order, unmarshaling each raw key via encoding.BinaryUnmarshaler into a fresh instance obtained from newKey.
+*/
+func (c *Cursorer) ForEachBinaryKey(newKey func() encoding.BinaryUnmarshaler, fn func(key encoding.BinaryUnmarshaler, value []byte) error) error {
+	return c.ForEach(func(k, v []byte) error {
+		key := newKey()
+		if err := key.UnmarshalBinary(k); err != nil {
+			return err
+		}
+		return fn(key, v)
+	})
+}
+
+/*
+ForEachJsonKey iterates over every key/value pair in the bucket in ascending key order, JSON-decoding each raw key into a fresh instance obtained from newKey.
+*/
+func (c *Cursorer) ForEachJsonKey(newKey func() interface{}, fn func(key interface{}, value []byte) error) error {
+	return c.ForEach(func(k, v []byte) error {
+		key := newKey()
+		if err := json.Unmarshal(k, key); err != nil {
+			return err
+		}
+		return fn(key, v)
+	})
+}
@@ -0,0 +1,123 @@
+package bucketeer
+
+import (
+	"testing"
+)
+
+func TestForRangeUint64Key(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "iter")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []uint64{50, 100, 150, 200, 250} {
+		if err := bb.ForUint64Key(k).PutUint64(k); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []uint64
+	err := bb.Cursor().ForRangeUint64Key(100, 200, func(key uint64, value []byte) error {
+		got = append(got, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint64{100, 150, 200}
+	if len(got) != len(want) {
+		t.Fatalf("ForRangeUint64Key(100, 200) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ForRangeUint64Key(100, 200) = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestForRangeInt64KeyAcrossZero(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "iter")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []int64{-100, -1, 0, 1, 100} {
+		if err := bb.ForInt64Key(k).PutInt64(k); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []int64
+	err := bb.Cursor().ForRangeInt64Key(-1, 1, func(key int64, value []byte) error {
+		got = append(got, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int64{-1, 0, 1}
+	if len(got) != len(want) {
+		t.Fatalf("ForRangeInt64Key(-1, 1) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ForRangeInt64Key(-1, 1) = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestReverseForEachUint64Key(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "iter")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []uint64{1, 2, 3} {
+		if err := bb.ForUint64Key(k).PutUint64(k); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []uint64
+	err := bb.Cursor().ReverseForEachUint64Key(func(key uint64, value []byte) error {
+		got = append(got, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint64{3, 2, 1}
+	if len(got) != len(want) {
+		t.Fatalf("ReverseForEachUint64Key() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("ReverseForEachUint64Key() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestForEachPrefixStringKey(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "iter")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"foo/a", "foo/b", "bar/a"} {
+		if err := bb.ForStringKey(k).Put([]byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	err := bb.Cursor().ForEachPrefixStringKey("foo/", func(key string, value []byte) error {
+		got = append(got, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("ForEachPrefixStringKey(foo/) = %v, want 2 entries", got)
+	}
+}
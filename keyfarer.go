@@ -0,0 +1,59 @@
+package bucketeer
+
+import "github.com/boltdb/bolt"
+
+/*
+Keyfarer resolves a single key within a Bucketeer's bucket and provides convenience methods for
+reading and writing its raw value. Keyfarers are created by Bucketeer.ForKey and its typed
+variants (ForByteKey, ForStringKey, ForUint64Key, etc) rather than constructed directly.
+*/
+type Keyfarer struct {
+	bb  *Bucketeer
+	key []byte
+}
+
+/*
+NewKeyfarer creates a Keyfarer for the provided Bucketeer and raw key bytes.
+*/
+func NewKeyfarer(bb *Bucketeer, key []byte) *Keyfarer {
+	return &Keyfarer{bb: bb, key: key}
+}
+
+/*
+Put stores value under the Keyfarer's key, updating every Index registered against the
+Keyfarer's Bucketeer in the same transaction. Every other Put method on Keyfarer and Bucketeer
+goes through the same reindexing write as this one, so defining an Index keeps all of them in sync.
+*/
+func (kf *Keyfarer) Put(value []byte) error {
+	return kf.putIndexed(value)
+}
+
+/*
+Get retrieves the value stored under the Keyfarer's key, or nil if there is none.
+*/
+func (kf *Keyfarer) Get() (value []byte, err error) {
+	bf := func(b *bolt.Bucket) error {
+		value = b.Get(kf.key)
+		return nil
+	}
+	err = kf.bb.View(bf)
+	return
+}
+
+/*
+Delete removes the value stored under the Keyfarer's key, removing it from every Index
+registered against the Keyfarer's Bucketeer in the same transaction.
+*/
+func (kf *Keyfarer) Delete() error {
+	bf := func(b *bolt.Bucket) error {
+		value := b.Get(kf.key)
+		if value == nil {
+			return nil
+		}
+		if err := kf.bb.removeIndexes(b, kf.key, value); err != nil {
+			return err
+		}
+		return b.Delete(kf.key)
+	}
+	return kf.bb.Update(bf)
+}
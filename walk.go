@@ -0,0 +1,169 @@
+package bucketeer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/boltdb/bolt"
+)
+
+/*
+Walk recursively descends into every nested sub-bucket beneath the Bucketeer's path, invoking fn for every key/value pair it finds. Sub-buckets are identified by the standard bbolt convention of a nil value and are descended into rather than passed to fn.
+*/
+func (bb *Bucketeer) Walk(fn func(path Path, key, value []byte) error) error {
+	return bb.View(func(b *bolt.Bucket) error {
+		return walkBucket(b, bb.path, fn)
+	})
+}
+
+func walkBucket(b *bolt.Bucket, path Path, fn func(path Path, key, value []byte) error) error {
+	return b.ForEach(func(k, v []byte) error {
+		if v == nil {
+			return walkBucket(b.Bucket(k), path.Nest(string(k)), fn)
+		}
+		return fn(path, k, v)
+	})
+}
+
+/*
+bucketSnapshot holds an in-memory copy of a bucket's key/value pairs and nested sub-buckets, so CopyTo can finish reading the source before it opens a write transaction on the destination. Opening a View and an Update transaction on the same *bolt.DB at the same time from one goroutine recursively read-locks the database's mmap lock and deadlocks on commit, so CopyTo must not nest the two.
+*/
+type bucketSnapshot struct {
+	values  map[string][]byte
+	buckets map[string]*bucketSnapshot
+}
+
+func snapshotBucket(b *bolt.Bucket) (*bucketSnapshot, error) {
+	snap := &bucketSnapshot{values: make(map[string][]byte), buckets: make(map[string]*bucketSnapshot)}
+	err := b.ForEach(func(k, v []byte) error {
+		if v == nil {
+			sub, err := snapshotBucket(b.Bucket(k))
+			if err != nil {
+				return err
+			}
+			snap.buckets[string(k)] = sub
+			return nil
+		}
+		snap.values[string(k)] = append([]byte(nil), v...)
+		return nil
+	})
+	return snap, err
+}
+
+func writeSnapshot(b *bolt.Bucket, snap *bucketSnapshot) error {
+	for k, v := range snap.values {
+		if err := b.Put([]byte(k), v); err != nil {
+			return err
+		}
+	}
+	for k, sub := range snap.buckets {
+		dstSub, err := b.CreateBucketIfNotExists([]byte(k))
+		if err != nil {
+			return err
+		}
+		if err := writeSnapshot(dstSub, sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+CopyTo copies every key/value pair and nested sub-bucket from bb into dst, creating dst's buckets as needed. The source subtree is read fully into memory before the destination transaction opens, so CopyTo is safe to use when bb and dst share the same *bolt.DB (the common case for snapshotting or migrating a sub-tree in place). If bb's own bucket path does not exist, CopyTo is a no-op.
+*/
+func (bb *Bucketeer) CopyTo(dst *Bucketeer) error {
+	var snap *bucketSnapshot
+	if err := bb.View(func(b *bolt.Bucket) (err error) {
+		snap, err = snapshotBucket(b)
+		return
+	}); err != nil {
+		return err
+	}
+	if snap == nil {
+		return nil
+	}
+	if err := dst.EnsurePathBuckets(); err != nil {
+		return err
+	}
+	return dst.Update(func(b *bolt.Bucket) error {
+		return writeSnapshot(b, snap)
+	})
+}
+
+/*
+ExportJSON writes the Bucketeer's bucket, including nested sub-buckets, to w as JSON. Keys and values are base64-encoded; nested buckets are encoded as nested objects keyed by their own base64-encoded key, so the result round-trips losslessly through ImportJSON.
+*/
+func (bb *Bucketeer) ExportJSON(w io.Writer) error {
+	var doc map[string]interface{}
+	err := bb.View(func(b *bolt.Bucket) (err error) {
+		doc, err = exportBucket(b)
+		return
+	})
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(doc)
+}
+
+func exportBucket(b *bolt.Bucket) (map[string]interface{}, error) {
+	doc := make(map[string]interface{})
+	err := b.ForEach(func(k, v []byte) error {
+		key := base64.StdEncoding.EncodeToString(k)
+		if v == nil {
+			nested, err := exportBucket(b.Bucket(k))
+			if err != nil {
+				return err
+			}
+			doc[key] = nested
+			return nil
+		}
+		doc[key] = base64.StdEncoding.EncodeToString(v)
+		return nil
+	})
+	return doc, err
+}
+
+/*
+ImportJSON reads a document produced by ExportJSON from r and writes its key/value pairs and nested sub-buckets into the Bucketeer's bucket, creating sub-buckets as needed.
+*/
+func (bb *Bucketeer) ImportJSON(r io.Reader) error {
+	var doc map[string]interface{}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return err
+	}
+	return bb.Update(func(b *bolt.Bucket) error {
+		return importBucket(b, doc)
+	})
+}
+
+func importBucket(b *bolt.Bucket, doc map[string]interface{}) error {
+	for encodedKey, raw := range doc {
+		key, err := base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return err
+		}
+		switch value := raw.(type) {
+		case string:
+			data, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return err
+			}
+			if err := b.Put(key, data); err != nil {
+				return err
+			}
+		case map[string]interface{}:
+			sub, err := b.CreateBucketIfNotExists(key)
+			if err != nil {
+				return err
+			}
+			if err := importBucket(sub, value); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("bucketeer: unexpected JSON value for key %q", encodedKey)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package bucketeer
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+func openScratchDB(t *testing.T) *bolt.DB {
+	f, err := os.CreateTemp("", "bucketeer-walk-*.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	path := f.Name()
+	t.Cleanup(func() { os.Remove(path) })
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestCopyToSameDBContents(t *testing.T) {
+	db := openScratchDB(t)
+	src := New(db, "src")
+	dst := New(db, "dst")
+	if err := src.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Update(func(b *bolt.Bucket) error {
+		if err := b.Put([]byte("a"), []byte("1")); err != nil {
+			return err
+		}
+		sub, err := b.CreateBucketIfNotExists([]byte("nested"))
+		if err != nil {
+			return err
+		}
+		return sub.Put([]byte("b"), []byte("2"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- src.CopyTo(dst) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("CopyTo deadlocked on same-DB copy")
+	}
+
+	if err := dst.View(func(b *bolt.Bucket) error {
+		if v := b.Get([]byte("a")); !bytes.Equal(v, []byte("1")) {
+			t.Errorf("dst[a] = %q, want %q", v, "1")
+		}
+		sub := b.Bucket([]byte("nested"))
+		if sub == nil {
+			t.Fatal("expected nested bucket to be copied")
+		}
+		if v := sub.Get([]byte("b")); !bytes.Equal(v, []byte("2")) {
+			t.Errorf("dst[nested/b] = %q, want %q", v, "2")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCopyToMissingSourceBucketIsNoop(t *testing.T) {
+	db := openScratchDB(t)
+	src := New(db, "src")
+	dst := New(db, "dst")
+
+	if err := src.CopyTo(dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte("dst")) != nil {
+			t.Error("expected CopyTo to leave dst untouched when src does not exist")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWalkVisitsNestedSubBuckets(t *testing.T) {
+	db := openScratchDB(t)
+	bb := New(db, "tree")
+	if err := bb.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bb.Update(func(b *bolt.Bucket) error {
+		if err := b.Put([]byte("a"), []byte("1")); err != nil {
+			return err
+		}
+		sub, err := b.CreateBucketIfNotExists([]byte("nested"))
+		if err != nil {
+			return err
+		}
+		if err := sub.Put([]byte("b"), []byte("2")); err != nil {
+			return err
+		}
+		subsub, err := sub.CreateBucketIfNotExists([]byte("deeper"))
+		if err != nil {
+			return err
+		}
+		return subsub.Put([]byte("c"), []byte("3"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var visited []string
+	err := bb.Walk(func(path Path, key, value []byte) error {
+		visited = append(visited, path.String()+"/"+string(key)+"="+string(value))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(visited)
+	want := []string{"tree/a=1", "tree/nested/b=2", "tree/nested/deeper/c=3"}
+	sort.Strings(want)
+	if len(visited) != len(want) {
+		t.Fatalf("Walk visited %v, want %v", visited, want)
+	}
+	for i := range want {
+		if visited[i] != want[i] {
+			t.Errorf("Walk visited %v, want %v", visited, want)
+			break
+		}
+	}
+}
+
+func TestExportImportJSONRoundTrip(t *testing.T) {
+	db := openScratchDB(t)
+	src := New(db, "export-src")
+	dst := New(db, "export-dst")
+	if err := src.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Update(func(b *bolt.Bucket) error {
+		if err := b.Put([]byte("a"), []byte("1")); err != nil {
+			return err
+		}
+		sub, err := b.CreateBucketIfNotExists([]byte("nested"))
+		if err != nil {
+			return err
+		}
+		return sub.Put([]byte("b"), []byte("2"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := src.ExportJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dst.EnsurePathBuckets(); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.ImportJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dst.View(func(b *bolt.Bucket) error {
+		if v := b.Get([]byte("a")); !bytes.Equal(v, []byte("1")) {
+			t.Errorf("dst[a] = %q, want %q", v, "1")
+		}
+		sub := b.Bucket([]byte("nested"))
+		if sub == nil {
+			t.Fatal("expected nested bucket to round-trip")
+		}
+		if v := sub.Get([]byte("b")); !bytes.Equal(v, []byte("2")) {
+			t.Errorf("dst[nested/b] = %q, want %q", v, "2")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}